@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"container/heap"
 	"log/slog"
 	"os"
 	"sync"
@@ -10,19 +11,39 @@ import (
 )
 
 type Cache struct {
-	items             map[string]Item
+	items       map[string]Item
+	expirations expirationHeap
+	// generations holds the cache-wide-unique generation stamped on a key's
+	// current live Set, so a stale expirationEntry popped off expirations can
+	// be recognized as such. It's deleted for a key entirely once that key is
+	// gone for good (not just bumped), so it doesn't grow without bound for
+	// workloads with many short-lived keys. Generations come from a single
+	// monotonic nextGeneration counter, not a per-key one, so a value is never
+	// reused - otherwise a pruned-then-reinserted key could collide with a
+	// still-pending stale expirationEntry from its previous life.
+	generations       map[string]uint64
+	nextGeneration    uint64
 	defaultExpiration time.Duration
 	cleanupInterval   time.Duration
 	stopCleanup       chan any
 	clock             clockwork.Clock
 	mu                sync.RWMutex
 	logger            *slog.Logger
+	onEvicted         func(key string, value any, reason EvictionReason)
+	fastDelete        bool
+	maxItems          int
+	evictionPolicy    EvictionPolicy
+	capacityState
+
+	loadMu sync.Mutex
+	loads  map[string]*loadCall
 }
 
 // Use NewDefaultCache or this to create a Cache instance, prefer not to refernece it directly
-func NewCache(defaultExpiration, cleanupInterval time.Duration, clock clockwork.Clock) *Cache {
+func NewCache(defaultExpiration, cleanupInterval time.Duration, clock clockwork.Clock, opts ...Option) *Cache {
 	cache := &Cache{
 		items:             make(map[string]Item),
+		generations:       make(map[string]uint64),
 		defaultExpiration: defaultExpiration,
 		cleanupInterval:   cleanupInterval,
 		stopCleanup:       make(chan any),
@@ -30,6 +51,10 @@ func NewCache(defaultExpiration, cleanupInterval time.Duration, clock clockwork.
 		logger:            setupLogger(),
 	}
 
+	for _, opt := range opts {
+		opt(cache)
+	}
+
 	if cleanupInterval > 0 {
 		go cache.startCleanupTimer()
 	}
@@ -38,8 +63,8 @@ func NewCache(defaultExpiration, cleanupInterval time.Duration, clock clockwork.
 }
 // Create a cache with some obvious defaults set. This is probably the version that you want.
 // See NewCache for more complex version
-func NewDefaultCache(defaultExpiration time.Duration) *Cache {
-	return NewCache(defaultExpiration, 30 * time.Second, clockwork.NewRealClock())
+func NewDefaultCache(defaultExpiration time.Duration, opts ...Option) *Cache {
+	return NewCache(defaultExpiration, 30*time.Second, clockwork.NewRealClock(), opts...)
 }
 
 // Add an item to the cache with the default expiration time.
@@ -57,11 +82,39 @@ func (c *Cache) SetWithExpiration(key string, value any, expiration time.Duratio
 	}
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	old, hadOld := c.items[key]
+	hadLiveOld := hadOld && (old.Expiration == 0 || c.clock.Now().UnixNano() < old.Expiration)
 	c.items[key] = Item{
 		Value:      value,
 		Expiration: exp,
 	}
+	c.nextGeneration++
+	c.generations[key] = c.nextGeneration
+	if 0 < exp {
+		heap.Push(&c.expirations, expirationEntry{key: key, expiration: exp, generation: c.generations[key]})
+	}
+
+	var capEvictedKey string
+	var capEvictedValue any
+	var capEvicted bool
+	if c.capacityEnabled() {
+		// Evict from the existing tracked items *before* tracking this Set, so a
+		// brand-new key being inserted over capacity can't end up evicting
+		// itself (e.g. under PolicyLFU, it would otherwise be alone in the
+		// minFreq==1 bucket the instant it's tracked).
+		if !hadOld && c.maxItems < len(c.items) {
+			capEvictedKey, capEvictedValue, capEvicted = c.evictForCapacity()
+		}
+		c.trackCapacitySet(key, !hadOld)
+	}
+	c.mu.Unlock()
+
+	if hadLiveOld && c.onEvicted != nil {
+		c.onEvicted(key, old.Value, EvictedReplaced)
+	}
+	if capEvicted && c.onEvicted != nil {
+		c.onEvicted(capEvictedKey, capEvictedValue, EvictedCapacity)
+	}
 }
 
 // Adds an item to the cache that never expires
@@ -72,28 +125,90 @@ func (c *Cache) SetWithoutExpiration(key string, value any) {
 // Manually remove an item from the cache. You will usually not have to do this and can either overwrite values in the cache and/or wait for them to expire
 func (c *Cache) Delete(key string) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	if c.fastDelete && c.onEvicted == nil {
+		delete(c.items, key)
+		delete(c.generations, key)
+		c.forgetCapacity(key)
+		c.mu.Unlock()
+		return
+	}
+
+	item, found := c.items[key]
 	delete(c.items, key)
+	delete(c.generations, key)
+	c.forgetCapacity(key)
+	c.mu.Unlock()
+
+	if found && c.onEvicted != nil {
+		c.onEvicted(key, item.Value, EvictedDeleted)
+	}
 }
 
 // Return cached item and a boolean indicating whether the key was found
 func (c *Cache) Get(key string) (any, bool) {
 	c.mu.RLock()
-	defer c.mu.RUnlock()
-
 	item, found := c.items[key]
+	c.mu.RUnlock()
+
 	if !found {
 		return nil, false
 	}
 
 	if 0 < item.Expiration && item.Expiration < c.clock.Now().UnixNano() {
 		c.logger.Debug("Item with key `", key, "` found but expired. Not returning.")
+		c.removeIfStillExpired(key, item.Expiration)
 		return nil, false
 	}
 
+	if c.capacityEnabled() {
+		c.mu.Lock()
+		if _, stillFound := c.items[key]; stillFound {
+			c.touchCapacityOnGet(key)
+		}
+		c.mu.Unlock()
+	}
+
 	return item.Value, true
 }
 
+// removeIfStillExpired opportunistically evicts the item under key if it's
+// still present with the expiration Get just observed. This lets a cache with
+// few expiring items among many long-lived ones shed expired entries as they're
+// touched, rather than waiting for the next cleanup tick to find them.
+func (c *Cache) removeIfStillExpired(key string, observedExpiration int64) {
+	c.mu.Lock()
+	item, found := c.items[key]
+	stillExpired := found && item.Expiration == observedExpiration
+	if stillExpired {
+		delete(c.items, key)
+		delete(c.generations, key)
+		c.forgetCapacity(key)
+	}
+	c.mu.Unlock()
+
+	if stillExpired && c.onEvicted != nil {
+		c.onEvicted(key, item.Value, EvictedExpired)
+	}
+}
+
+// NextExpiration returns the expiration time of the next item due to expire,
+// so callers can schedule cleanup precisely instead of at a fixed interval. It
+// returns the zero time if nothing in the cache is due to expire.
+func (c *Cache) NextExpiration() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for 0 < len(c.expirations) {
+		top := c.expirations[0]
+		if c.generations[top.key] == top.generation {
+			return time.Unix(0, top.expiration)
+		}
+		heap.Pop(&c.expirations)
+	}
+
+	return time.Time{}
+}
+
 type Item struct {
 	Value      any
 	Expiration int64
@@ -138,21 +253,45 @@ func (c *Cache) startCleanupTimer() {
 	}
 }
 
-// Force eviction of all expired items
+// Force eviction of all expired items. Rather than scanning every item in the
+// map, this pops the expiration heap while its top is due, which is O(k log N)
+// for k actually-expired items instead of O(N).
 func (c *Cache) deleteExpired() {
 	c.logger.Debug("Cleaning up expired items.")
 	now := c.clock.Now().UnixNano()
 
+	// Evicted entries are collected here and the callback invoked after mu is
+	// released, so user code in the hook never runs while holding the lock.
+	var evicted []evictedEntry
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	for 0 < len(c.expirations) && c.expirations[0].expiration <= now {
+		entry := heap.Pop(&c.expirations).(expirationEntry)
 
-	for k, v := range c.items {
-		if 0 < v.Expiration && v.Expiration < now {
-			delete(c.items, k)
+		item, found := c.items[entry.key]
+		if !found || c.generations[entry.key] != entry.generation {
+			continue // key was overwritten or deleted since this entry was scheduled
 		}
+
+		if c.onEvicted != nil {
+			evicted = append(evicted, evictedEntry{key: entry.key, value: item.Value})
+		}
+		delete(c.items, entry.key)
+		delete(c.generations, entry.key)
+		c.forgetCapacity(entry.key)
+	}
+	c.mu.Unlock()
+
+	for _, e := range evicted {
+		c.onEvicted(e.key, e.value, EvictedExpired)
 	}
 }
 
+type evictedEntry struct {
+	key   string
+	value any
+}
+
 // Stops the cleanup and properly disposes of the cache
 func (c *Cache) Stop() {
 	if 0 < c.cleanupInterval {
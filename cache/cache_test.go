@@ -161,9 +161,11 @@ func TestCacheCleanup(t *testing.T) {
 			t.Errorf("Expected item1 to be expired, but got %v", val)
 		}
 
+		// Get opportunistically removes the expired entry it just observed, so
+		// item1 is already gone even though the cleanup tick hasn't fired yet.
 		cache.mu.RLock()
-		if len(cache.items) != 2 {
-			t.Errorf("Expected 2 items still in internal map before cleanup, got %d", len(cache.items))
+		if len(cache.items) != 1 {
+			t.Errorf("Expected 1 item still in internal map before cleanup, got %d", len(cache.items))
 		}
 		cache.mu.RUnlock()
 
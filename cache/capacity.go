@@ -0,0 +1,211 @@
+package cache
+
+import "container/list"
+
+// EvictionPolicy selects how a capacity-bounded Cache (see WithMaxItems)
+// chooses what to evict once it's full.
+type EvictionPolicy int
+
+const (
+	// PolicyLRU evicts the least-recently-used item: the list is reordered on
+	// every Get and Set, and eviction takes from the back of that list.
+	PolicyLRU EvictionPolicy = iota
+	// PolicyLFU evicts the least-frequently-used item, breaking ties by age
+	// within the lowest frequency bucket.
+	PolicyLFU
+	// PolicyFIFO evicts in insertion order regardless of how often or recently
+	// an item was accessed.
+	PolicyFIFO
+)
+
+// WithMaxItems bounds the cache to at most n items. Once full, Set evicts one
+// item according to the configured EvictionPolicy (WithEvictionPolicy) before
+// inserting the new one, firing the OnEvicted hook with EvictedCapacity.
+func WithMaxItems(n int) Option {
+	return func(c *Cache) {
+		c.maxItems = n
+	}
+}
+
+// WithEvictionPolicy selects the capacity-eviction policy used once
+// WithMaxItems is set. Defaults to PolicyLRU.
+func WithEvictionPolicy(policy EvictionPolicy) Option {
+	return func(c *Cache) {
+		c.evictionPolicy = policy
+	}
+}
+
+// capacityState holds the bookkeeping needed by the capacity-bounded eviction
+// policies. It's left zero-valued (and unused) on a Cache that has no
+// WithMaxItems configured.
+type capacityState struct {
+	// lruList and lruElems back both PolicyLRU and PolicyFIFO: most-recently
+	// touched/inserted at the front, eviction from the back. PolicyFIFO simply
+	// never re-touches an element on Get.
+	lruList  *list.List
+	lruElems map[string]*list.Element
+
+	// freqBuckets/freqElems/itemFreq/minFreq back PolicyLFU: itemFreq tracks
+	// each key's access count, freqBuckets buckets keys by that count (each
+	// bucket itself ordered LRU-style so ties break by age), and minFreq
+	// always points at the lowest non-empty bucket.
+	freqBuckets map[int]*list.List
+	freqElems   map[string]*list.Element
+	itemFreq    map[string]int
+	minFreq     int
+}
+
+func (c *Cache) capacityEnabled() bool {
+	return 0 < c.maxItems
+}
+
+// initCapacityState lazily sets up the structures needed by the configured
+// eviction policy. Called under c.mu from the first capacity-tracked Set.
+func (c *Cache) initCapacityState() {
+	if c.lruList != nil || c.freqBuckets != nil {
+		return
+	}
+	switch c.evictionPolicy {
+	case PolicyLFU:
+		c.freqBuckets = make(map[int]*list.List)
+		c.freqElems = make(map[string]*list.Element)
+		c.itemFreq = make(map[string]int)
+	default: // PolicyLRU, PolicyFIFO
+		c.lruList = list.New()
+		c.lruElems = make(map[string]*list.Element)
+	}
+}
+
+// trackCapacitySet records key as just inserted/overwritten, for eviction
+// bookkeeping purposes. Must be called under c.mu.
+func (c *Cache) trackCapacitySet(key string, isNew bool) {
+	c.initCapacityState()
+
+	switch c.evictionPolicy {
+	case PolicyLRU:
+		c.touchLRU(key)
+	case PolicyFIFO:
+		if isNew {
+			c.touchLRU(key)
+		}
+	case PolicyLFU:
+		c.touchLFU(key)
+	}
+}
+
+// touchCapacityOnGet records a successful Get against key for policies that
+// care about access patterns. Must be called under c.mu, and only once the
+// caller has confirmed key is still present.
+func (c *Cache) touchCapacityOnGet(key string) {
+	switch c.evictionPolicy {
+	case PolicyLRU:
+		c.touchLRU(key)
+	case PolicyLFU:
+		c.touchLFU(key)
+	case PolicyFIFO:
+		// insertion order only; accessing an item doesn't change it
+	}
+}
+
+// forgetCapacity drops any eviction bookkeeping for key, e.g. because it was
+// deleted or expired outside of the normal capacity-eviction path. Must be
+// called under c.mu.
+func (c *Cache) forgetCapacity(key string) {
+	if !c.capacityEnabled() {
+		return
+	}
+	switch c.evictionPolicy {
+	case PolicyLRU, PolicyFIFO:
+		c.removeFromLRU(key)
+	case PolicyLFU:
+		if freq, ok := c.itemFreq[key]; ok {
+			c.removeFromFreqBucket(key, freq)
+			delete(c.itemFreq, key)
+		}
+	}
+}
+
+func (c *Cache) touchLRU(key string) {
+	if elem, ok := c.lruElems[key]; ok {
+		c.lruList.MoveToFront(elem)
+		return
+	}
+	c.lruElems[key] = c.lruList.PushFront(key)
+}
+
+func (c *Cache) removeFromLRU(key string) {
+	if elem, ok := c.lruElems[key]; ok {
+		c.lruList.Remove(elem)
+		delete(c.lruElems, key)
+	}
+}
+
+func (c *Cache) touchLFU(key string) {
+	freq, existed := c.itemFreq[key]
+	if existed {
+		c.removeFromFreqBucket(key, freq)
+	} else {
+		freq = 0
+	}
+	freq++
+
+	c.itemFreq[key] = freq
+	bucket, ok := c.freqBuckets[freq]
+	if !ok {
+		bucket = list.New()
+		c.freqBuckets[freq] = bucket
+	}
+	c.freqElems[key] = bucket.PushFront(key)
+
+	if !existed || c.minFreq == 0 {
+		c.minFreq = 1
+	}
+}
+
+// removeFromFreqBucket removes key from the frequency bucket it's in and
+// advances minFreq past it if that was the lowest bucket and it's now empty.
+func (c *Cache) removeFromFreqBucket(key string, freq int) {
+	bucket := c.freqBuckets[freq]
+	if bucket == nil {
+		return
+	}
+	if elem, ok := c.freqElems[key]; ok {
+		bucket.Remove(elem)
+		delete(c.freqElems, key)
+	}
+	if bucket.Len() == 0 {
+		delete(c.freqBuckets, freq)
+		if c.minFreq == freq {
+			c.minFreq++
+		}
+	}
+}
+
+// evictForCapacity picks a victim under the configured policy, removes it from
+// items and its bookkeeping, and reports what was evicted. Must be called
+// under c.mu; ok is false if there was nothing to evict.
+func (c *Cache) evictForCapacity() (key string, value any, ok bool) {
+	switch c.evictionPolicy {
+	case PolicyLFU:
+		bucket := c.freqBuckets[c.minFreq]
+		if bucket == nil || bucket.Len() == 0 {
+			return "", nil, false
+		}
+		elem := bucket.Back()
+		key = elem.Value.(string)
+		c.removeFromFreqBucket(key, c.minFreq)
+		delete(c.itemFreq, key)
+	default: // PolicyLRU, PolicyFIFO
+		elem := c.lruList.Back()
+		if elem == nil {
+			return "", nil, false
+		}
+		key = elem.Value.(string)
+		c.removeFromLRU(key)
+	}
+
+	item := c.items[key]
+	delete(c.items, key)
+	delete(c.generations, key)
+	return key, item.Value, true
+}
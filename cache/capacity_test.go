@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	clockwork "github.com/jonboulle/clockwork"
+)
+
+func TestCapacityLRUEviction(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	var evicted []recordedEviction
+	cache := NewCache(0, 0, clock,
+		WithMaxItems(2),
+		WithEvictionPolicy(PolicyLRU),
+		WithOnEvicted(func(key string, value any, reason EvictionReason) {
+			evicted = append(evicted, recordedEviction{key, value, reason})
+		}),
+	)
+	defer cache.Stop()
+
+	t.Run("when 2 items are set, filling the cache", func(t *testing.T) {
+		cache.Set("a", "a value")
+		cache.Set("b", "b value")
+
+		t.Run("when `a` is read, making `b` the least-recently-used", func(t *testing.T) {
+			cache.Get("a")
+
+			t.Run("when a third item is set", func(t *testing.T) {
+				cache.Set("c", "c value")
+
+				t.Run("THEN `b` is evicted with EvictedCapacity", func(t *testing.T) {
+					if _, found := cache.Get("b"); found {
+						t.Errorf("Expected `b` to have been evicted")
+					}
+					if len(evicted) != 1 || evicted[0].key != "b" || evicted[0].reason != EvictedCapacity {
+						t.Errorf("Expected a single EvictedCapacity eviction of `b`, got %+v", evicted)
+					}
+				})
+
+				t.Run("THEN `a` and `c` are both still present", func(t *testing.T) {
+					if _, found := cache.Get("a"); !found {
+						t.Errorf("Expected `a` to still be in cache")
+					}
+					if _, found := cache.Get("c"); !found {
+						t.Errorf("Expected `c` to still be in cache")
+					}
+				})
+			})
+		})
+	})
+}
+
+func TestCapacityFIFOEviction(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewCache(0, 0, clock, WithMaxItems(2), WithEvictionPolicy(PolicyFIFO))
+	defer cache.Stop()
+
+	t.Run("when 2 items are set and the first is read repeatedly", func(t *testing.T) {
+		cache.Set("a", "a value")
+		cache.Set("b", "b value")
+		cache.Get("a")
+		cache.Get("a")
+
+		t.Run("when a third item is set", func(t *testing.T) {
+			cache.Set("c", "c value")
+
+			t.Run("THEN `a` is evicted anyway, since FIFO ignores access order", func(t *testing.T) {
+				if _, found := cache.Get("a"); found {
+					t.Errorf("Expected `a` to have been evicted despite being recently read")
+				}
+			})
+		})
+	})
+}
+
+func TestCapacityLFUEviction(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewCache(0, 0, clock, WithMaxItems(2), WithEvictionPolicy(PolicyLFU))
+	defer cache.Stop()
+
+	t.Run("when 2 items are set and `a` is read several times", func(t *testing.T) {
+		cache.Set("a", "a value")
+		cache.Set("b", "b value")
+		cache.Get("a")
+		cache.Get("a")
+
+		t.Run("when a third item is set", func(t *testing.T) {
+			cache.Set("c", "c value")
+
+			t.Run("THEN `b`, the least-frequently-used, is evicted instead of `a`", func(t *testing.T) {
+				if _, found := cache.Get("b"); found {
+					t.Errorf("Expected `b` to have been evicted")
+				}
+				if _, found := cache.Get("a"); !found {
+					t.Errorf("Expected `a` to survive, it was accessed more frequently")
+				}
+			})
+		})
+	})
+}
+
+func TestCapacityLFUDoesNotEvictTheJustInsertedKey(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewCache(0, 0, clock, WithMaxItems(2), WithEvictionPolicy(PolicyLFU))
+	defer cache.Stop()
+
+	t.Run("when both existing items have been accessed at least once", func(t *testing.T) {
+		cache.Set("a", "a value")
+		cache.Set("b", "b value")
+		cache.Get("a")
+		cache.Get("b")
+
+		t.Run("when a brand-new key is set", func(t *testing.T) {
+			cache.Set("c", "c value")
+
+			t.Run("THEN the new key survives instead of being evicted against itself", func(t *testing.T) {
+				if _, found := cache.Get("c"); !found {
+					t.Errorf("Expected `c` to be present immediately after being set")
+				}
+			})
+
+			t.Run("THEN exactly one of the pre-existing keys was evicted to make room", func(t *testing.T) {
+				_, aFound := cache.Get("a")
+				_, bFound := cache.Get("b")
+				if aFound == bFound {
+					t.Errorf("Expected exactly one of `a`/`b` to remain, got a=%v b=%v", aFound, bFound)
+				}
+			})
+		})
+	})
+}
+
+func TestCapacityDeleteClearsBookkeeping(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewCache(0, 0, clock, WithMaxItems(2))
+	defer cache.Stop()
+
+	t.Run("when an item is set and then explicitly deleted", func(t *testing.T) {
+		cache.Set("a", "a value")
+		cache.Delete("a")
+
+		t.Run("when 2 more items are set", func(t *testing.T) {
+			cache.Set("b", "b value")
+			cache.Set("c", "c value")
+
+			t.Run("THEN both are present, since the deleted item no longer counts against capacity", func(t *testing.T) {
+				if _, found := cache.Get("b"); !found {
+					t.Errorf("Expected `b` to be present")
+				}
+				if _, found := cache.Get("c"); !found {
+					t.Errorf("Expected `c` to be present")
+				}
+			})
+		})
+	})
+}
+
+func TestCapacityDisabledByDefault(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewCache(1*time.Second, 0, clock)
+	defer cache.Stop()
+
+	t.Run("when many items are set without WithMaxItems", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			cache.SetWithoutExpiration(string(rune('a'+i%26))+string(rune(i)), i)
+		}
+
+		t.Run("THEN nothing is evicted for capacity reasons", func(t *testing.T) {
+			if cache.capacityEnabled() {
+				t.Errorf("Expected capacity tracking to be disabled")
+			}
+		})
+	})
+}
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	clockwork "github.com/jonboulle/clockwork"
+)
+
+type recordedEviction struct {
+	key    string
+	value  any
+	reason EvictionReason
+}
+
+// drainEvictions collects whatever's already buffered in ch without blocking.
+// Safe to use right after a synchronous call like Set/Delete, since those
+// invoke the OnEvicted hook in the caller's own goroutine before returning.
+func drainEvictions(ch <-chan recordedEviction) []recordedEviction {
+	var got []recordedEviction
+	for {
+		select {
+		case e := <-ch:
+			got = append(got, e)
+		default:
+			return got
+		}
+	}
+}
+
+// waitForEviction blocks on ch until an eviction matching key/reason arrives
+// or timeout elapses, returning everything seen along the way. Needed for
+// evictions fired from the background cleanup goroutine, where there's no
+// synchronous happens-before relationship to rely on instead.
+func waitForEviction(ch <-chan recordedEviction, key string, reason EvictionReason, timeout time.Duration) []recordedEviction {
+	var got []recordedEviction
+	deadline := time.After(timeout)
+	for {
+		select {
+		case e := <-ch:
+			got = append(got, e)
+			if e.key == key && e.reason == reason {
+				return got
+			}
+		case <-deadline:
+			return got
+		}
+	}
+}
+
+func TestOnEvicted(t *testing.T) {
+	t.Run("when a cache has an OnEvicted hook registered", func(t *testing.T) {
+		evictions := make(chan recordedEviction, 16)
+		clock := clockwork.NewFakeClock()
+		cache := NewCache(1*time.Second, 2*time.Second, clock, WithOnEvicted(func(key string, value any, reason EvictionReason) {
+			evictions <- recordedEviction{key, value, reason}
+		}))
+		defer cache.Stop()
+
+		t.Run("when an item is deleted", func(t *testing.T) {
+			cache.Set("foo", "foo value")
+			drainEvictions(evictions)
+			cache.Delete("foo")
+
+			t.Run("THEN the hook fires with EvictedDeleted", func(t *testing.T) {
+				got := drainEvictions(evictions)
+				if len(got) != 1 {
+					t.Fatalf("Expected 1 eviction, got %d", len(got))
+				}
+				if got[0].reason != EvictedDeleted || got[0].value != "foo value" {
+					t.Errorf("Unexpected eviction: %+v", got[0])
+				}
+			})
+		})
+
+		t.Run("when a live item is overwritten", func(t *testing.T) {
+			cache.Set("bar", "bar value")
+			drainEvictions(evictions)
+			cache.Set("bar", "bar value 2")
+
+			t.Run("THEN the hook fires with EvictedReplaced for the old value", func(t *testing.T) {
+				got := drainEvictions(evictions)
+				if len(got) != 1 {
+					t.Fatalf("Expected 1 eviction, got %d", len(got))
+				}
+				if got[0].reason != EvictedReplaced || got[0].value != "bar value" {
+					t.Errorf("Unexpected eviction: %+v", got[0])
+				}
+			})
+		})
+
+		t.Run("when an item expires and cleanup runs", func(t *testing.T) {
+			cache.Set("baz", "baz value")
+			drainEvictions(evictions)
+			clock.Advance(2100 * time.Millisecond)
+
+			t.Run("THEN the hook fires with EvictedExpired", func(t *testing.T) {
+				// "bar" (set earlier with the default 1s expiration and never
+				// advanced before) may legitimately also expire on this same
+				// cleanup tick, so wait for the specific event instead of
+				// assuming it's the only or the first one.
+				got := waitForEviction(evictions, "baz", EvictedExpired, 2*time.Second)
+				found := false
+				for _, e := range got {
+					if e.key == "baz" && e.reason == EvictedExpired {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("Expected an EvictedExpired eviction for `baz`, got %+v", got)
+				}
+			})
+		})
+	})
+}
+
+func TestWithFastDelete(t *testing.T) {
+	t.Run("when a cache is created with WithFastDelete and no hook", func(t *testing.T) {
+		clock := clockwork.NewFakeClock()
+		cache := NewCache(1*time.Second, 0, clock, WithFastDelete())
+		defer cache.Stop()
+
+		cache.Set("foo", "foo value")
+		cache.Delete("foo")
+
+		t.Run("THEN the item is still removed", func(t *testing.T) {
+			if _, found := cache.Get("foo"); found {
+				t.Errorf("Expected `foo` to be deleted")
+			}
+		})
+	})
+}
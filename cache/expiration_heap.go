@@ -0,0 +1,33 @@
+package cache
+
+// expirationEntry is a scheduled expiration for a key, generation-stamped so a
+// later overwrite or delete of that key can be recognized as having made this
+// entry stale without having to scrub the heap eagerly.
+type expirationEntry struct {
+	key        string
+	expiration int64
+	generation uint64
+}
+
+// expirationHeap is a min-heap of expirationEntry ordered by expiration, used
+// to find the next key(s) due to expire in O(log N) instead of scanning every
+// item in the cache. It implements container/heap.Interface.
+type expirationHeap []expirationEntry
+
+func (h expirationHeap) Len() int { return len(h) }
+
+func (h expirationHeap) Less(i, j int) bool { return h[i].expiration < h[j].expiration }
+
+func (h expirationHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *expirationHeap) Push(x any) {
+	*h = append(*h, x.(expirationEntry))
+}
+
+func (h *expirationHeap) Pop() any {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	*h = old[:n-1]
+	return entry
+}
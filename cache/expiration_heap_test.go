@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	clockwork "github.com/jonboulle/clockwork"
+)
+
+func TestNextExpiration(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewCache(1*time.Second, 0, clock)
+	defer cache.Stop()
+
+	t.Run("when the cache is empty", func(t *testing.T) {
+		t.Run("THEN NextExpiration is the zero time", func(t *testing.T) {
+			if next := cache.NextExpiration(); !next.IsZero() {
+				t.Errorf("Expected zero time, got %v", next)
+			}
+		})
+	})
+
+	t.Run("when two items are set with different expirations", func(t *testing.T) {
+		cache.SetWithExpiration("soon", "soon value", 1*time.Second)
+		cache.SetWithExpiration("later", "later value", 10*time.Second)
+
+		t.Run("THEN NextExpiration reports the earlier one", func(t *testing.T) {
+			expected := clock.Now().Add(1 * time.Second)
+			if next := cache.NextExpiration(); !next.Equal(expected) {
+				t.Errorf("Expected %v, got %v", expected, next)
+			}
+		})
+
+		t.Run("when `soon` is overwritten without expiration", func(t *testing.T) {
+			cache.SetWithoutExpiration("soon", "soon value 2")
+
+			t.Run("THEN NextExpiration skips the now-stale heap entry and reports `later`", func(t *testing.T) {
+				expected := clock.Now().Add(10 * time.Second)
+				if next := cache.NextExpiration(); !next.Equal(expected) {
+					t.Errorf("Expected %v, got %v", expected, next)
+				}
+			})
+		})
+	})
+}
+
+func TestGenerationsArePrunedOnRemoval(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewCache(1*time.Second, 0, clock)
+	defer cache.Stop()
+
+	t.Run("when many distinct short-lived keys are set and then removed", func(t *testing.T) {
+		for i := 0; i < 1000; i++ {
+			key := "key-" + string(rune(i))
+			cache.SetWithExpiration(key, i, 1*time.Second)
+			cache.Delete(key)
+		}
+
+		t.Run("THEN the generations bookkeeping map doesn't grow without bound", func(t *testing.T) {
+			if got := len(cache.generations); got != 0 {
+				t.Errorf("Expected generations to be fully pruned, got %d entries", got)
+			}
+		})
+	})
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// loadCall represents an in-flight GetOrLoad call for a given key. The first
+// goroutine to arrive for a key creates one and runs the loader; goroutines
+// that arrive while it's in flight wait on wg instead of calling the loader
+// themselves.
+type loadCall struct {
+	wg  sync.WaitGroup
+	val any
+	err error
+}
+
+// GetOrLoad returns the cached value for key if present and unexpired.
+// Otherwise it calls loader exactly once - even if many goroutines call
+// GetOrLoad for the same key concurrently - stores the result with the
+// returned TTL (0 uses the cache's default expiration, negative means no
+// expiration), and returns it to every caller. If loader returns an error,
+// nothing is cached and the error is propagated to every waiter.
+func (c *Cache) GetOrLoad(key string, loader func() (any, time.Duration, error)) (any, error) {
+	if val, found := c.Get(key); found {
+		return val, nil
+	}
+
+	c.loadMu.Lock()
+	if c.loads == nil {
+		c.loads = make(map[string]*loadCall)
+	}
+	if call, inFlight := c.loads[key]; inFlight {
+		c.loadMu.Unlock()
+		call.wg.Wait()
+		return call.val, call.err
+	}
+
+	call := &loadCall{}
+	call.wg.Add(1)
+	c.loads[key] = call
+	c.loadMu.Unlock()
+
+	// Deferred so a panicking loader still releases waiters (instead of
+	// leaving them, and the key, stuck forever) and so the store into the
+	// cache below always happens before waiters are woken and the in-flight
+	// entry is cleared - otherwise a caller arriving in that gap would see
+	// neither an in-flight call nor a cached value and re-run the loader. If
+	// loader panics, recover it here just long enough to record call.err so
+	// coalesced waiters get the failure instead of a false-positive nil/nil,
+	// then re-panic so this goroutine's caller still observes it as before.
+	defer func() {
+		r := recover()
+		if r != nil {
+			call.err = fmt.Errorf("getOrLoad: loader panicked: %v", r)
+		}
+
+		c.loadMu.Lock()
+		delete(c.loads, key)
+		c.loadMu.Unlock()
+		call.wg.Done()
+
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	val, ttl, err := loader()
+	call.val, call.err = val, err
+
+	if err == nil {
+		c.SetWithExpiration(key, val, normalizeLoaderTTL(ttl, c.defaultExpiration))
+	}
+
+	return val, err
+}
+
+// normalizeLoaderTTL maps a GetOrLoad loader's TTL convention (0 = default
+// expiration, negative = no expiration) onto SetWithExpiration's (0 = no
+// expiration).
+func normalizeLoaderTTL(ttl, defaultExpiration time.Duration) time.Duration {
+	switch {
+	case ttl == 0:
+		return defaultExpiration
+	case ttl < 0:
+		return 0
+	default:
+		return ttl
+	}
+}
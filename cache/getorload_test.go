@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	clockwork "github.com/jonboulle/clockwork"
+)
+
+func TestGetOrLoadCoalescesConcurrentCallers(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewCache(1*time.Minute, 0, clock)
+	defer cache.Stop()
+
+	var loadCount int32
+	release := make(chan struct{})
+	loader := func() (any, time.Duration, error) {
+		atomic.AddInt32(&loadCount, 1)
+		<-release
+		return "loaded value", 0, nil
+	}
+
+	t.Run("when 10 goroutines call GetOrLoad for the same missing key concurrently", func(t *testing.T) {
+		const n = 10
+		results := make([]any, n)
+		var wg sync.WaitGroup
+		wg.Add(n)
+		for i := 0; i < n; i++ {
+			go func(i int) {
+				defer wg.Done()
+				val, err := cache.GetOrLoad("key", loader)
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				results[i] = val
+			}(i)
+		}
+
+		close(release)
+		wg.Wait()
+
+		t.Run("THEN the loader ran exactly once", func(t *testing.T) {
+			if got := atomic.LoadInt32(&loadCount); got != 1 {
+				t.Errorf("Expected loader to run once, ran %d times", got)
+			}
+		})
+
+		t.Run("THEN every caller got the loaded value", func(t *testing.T) {
+			for i, val := range results {
+				if val != "loaded value" {
+					t.Errorf("Caller %d got %v, expected `loaded value`", i, val)
+				}
+			}
+		})
+
+		t.Run("THEN the value is now cached directly", func(t *testing.T) {
+			val, found := cache.Get("key")
+			if !found || val != "loaded value" {
+				t.Errorf("Expected `loaded value` cached, got %v, found=%v", val, found)
+			}
+		})
+	})
+}
+
+func TestGetOrLoadPanickingLoaderReleasesWaitersWithAnError(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewCache(1*time.Minute, 0, clock)
+	defer cache.Stop()
+
+	proceed := make(chan struct{})
+	leaderStarted := make(chan struct{})
+	var leaderStartedOnce sync.Once
+	loader := func() (any, time.Duration, error) {
+		leaderStartedOnce.Do(func() { close(leaderStarted) })
+		<-proceed
+		panic("loader blew up")
+	}
+
+	t.Run("when the leader goroutine's loader panics while another goroutine is waiting on the same key", func(t *testing.T) {
+		leaderPanicked := make(chan struct{})
+		go func() {
+			defer close(leaderPanicked)
+			defer func() { recover() }()
+			cache.GetOrLoad("key", loader)
+		}()
+
+		// Wait for the leader to have registered itself as in-flight and
+		// entered the loader (leaderStarted closes right before it blocks on
+		// proceed) before starting the waiter, so the waiter deterministically
+		// joins the in-flight call instead of racing to become the leader.
+		<-leaderStarted
+
+		var waiterErr error
+		waiterDone := make(chan struct{})
+		go func() {
+			defer close(waiterDone)
+			_, waiterErr = cache.GetOrLoad("key", loader)
+		}()
+
+		close(proceed)
+
+		<-leaderPanicked
+		<-waiterDone
+
+		t.Run("THEN the waiter gets an error instead of a false-positive nil result", func(t *testing.T) {
+			if waiterErr == nil {
+				t.Errorf("Expected the waiter to receive an error after the leader's loader panicked")
+			}
+		})
+
+		t.Run("THEN the in-flight call was cleared, so a later call for the same key can retry", func(t *testing.T) {
+			val, err := cache.GetOrLoad("key", func() (any, time.Duration, error) {
+				return "retried value", 0, nil
+			})
+			if err != nil || val != "retried value" {
+				t.Errorf("Expected a fresh retry to succeed, got %v, err=%v", val, err)
+			}
+		})
+	})
+}
+
+func TestGetOrLoadPropagatesLoaderError(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewCache(1*time.Minute, 0, clock)
+	defer cache.Stop()
+
+	loaderErr := errors.New("boom")
+	t.Run("when the loader fails", func(t *testing.T) {
+		_, err := cache.GetOrLoad("key", func() (any, time.Duration, error) {
+			return nil, 0, loaderErr
+		})
+
+		t.Run("THEN the error is returned", func(t *testing.T) {
+			if err != loaderErr {
+				t.Errorf("Expected loaderErr, got %v", err)
+			}
+		})
+
+		t.Run("THEN nothing was cached", func(t *testing.T) {
+			if _, found := cache.Get("key"); found {
+				t.Errorf("Expected `key` to not be cached after a failed load")
+			}
+		})
+	})
+}
+
+func TestGetOrLoadReturnsCachedValueWithoutCallingLoader(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewCache(1*time.Minute, 0, clock)
+	defer cache.Stop()
+	cache.Set("key", "already cached")
+
+	t.Run("when GetOrLoad is called for an already-cached key", func(t *testing.T) {
+		called := false
+		val, err := cache.GetOrLoad("key", func() (any, time.Duration, error) {
+			called = true
+			return "loaded value", 0, nil
+		})
+
+		t.Run("THEN the loader is never invoked and the cached value is returned", func(t *testing.T) {
+			if called {
+				t.Errorf("Expected loader not to be called")
+			}
+			if err != nil || val != "already cached" {
+				t.Errorf("Expected `already cached`, got %v, err=%v", val, err)
+			}
+		})
+	})
+}
@@ -0,0 +1,36 @@
+package cache
+
+// EvictionReason describes why an item left the cache, passed to an OnEvicted hook.
+type EvictionReason int
+
+const (
+	// EvictedExpired means the item was removed because its TTL elapsed.
+	EvictedExpired EvictionReason = iota
+	// EvictedDeleted means the item was removed via an explicit Delete call.
+	EvictedDeleted
+	// EvictedReplaced means the item was overwritten by a Set/SetWithExpiration call while still live.
+	EvictedReplaced
+	// EvictedCapacity means the item was evicted to make room under a capacity limit.
+	EvictedCapacity
+)
+
+// Option configures a Cache at construction time. See NewCache.
+type Option func(*Cache)
+
+// WithOnEvicted registers a hook that is called whenever an item leaves the
+// cache, along with the reason it left. The hook is invoked without c.mu held,
+// so it's safe for it to call back into the cache.
+func WithOnEvicted(fn func(key string, value any, reason EvictionReason)) Option {
+	return func(c *Cache) {
+		c.onEvicted = fn
+	}
+}
+
+// WithFastDelete skips the bookkeeping Delete otherwise does to report the
+// evicted value to an OnEvicted hook. It only takes effect when no hook is
+// registered; with a hook registered, Delete always looks up the old value.
+func WithFastDelete() Option {
+	return func(c *Cache) {
+		c.fastDelete = true
+	}
+}
@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"container/heap"
+	"encoding/gob"
+	"io"
+	"os"
+	"time"
+
+	clockwork "github.com/jonboulle/clockwork"
+)
+
+// persistedItem is the on-disk shape of a cache entry. Expiration is stored as
+// a TTL remaining at save time (0 meaning "no expiration") rather than an
+// absolute timestamp, so a snapshot loaded at a different wall-clock time -
+// e.g. after a process restart - still expires items at the right moment.
+type persistedItem struct {
+	Key   string
+	Value any
+	TTL   time.Duration
+}
+
+// Save writes a snapshot of the cache to w via encoding/gob. Because Value is
+// `any`, callers must gob.Register the concrete types they store in the cache
+// beforehand; an item whose value can't be gob-encoded (e.g. its type was
+// never registered) is skipped and logged at WARN rather than aborting the
+// whole snapshot.
+func (c *Cache) Save(w io.Writer) error {
+	now := c.clock.Now().UnixNano()
+
+	c.mu.RLock()
+	items := make([]persistedItem, 0, len(c.items))
+	for k, v := range c.items {
+		if 0 < v.Expiration && v.Expiration <= now {
+			continue // already expired, not worth persisting
+		}
+		var ttl time.Duration
+		if 0 < v.Expiration {
+			ttl = time.Duration(v.Expiration - now)
+		}
+		items = append(items, persistedItem{Key: k, Value: v.Value, TTL: ttl})
+	}
+	c.mu.RUnlock()
+
+	enc := gob.NewEncoder(w)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			c.logger.Warn("Skipping item that failed to gob-encode", "key", item.Key, "error", err)
+			continue
+		}
+	}
+	return nil
+}
+
+// Load restores items from a snapshot written by Save, adding them to the
+// cache with their remaining TTL measured from now rather than from when they
+// were saved. Existing items under the same keys are overwritten.
+func (c *Cache) Load(r io.Reader) error {
+	dec := gob.NewDecoder(r)
+
+	for {
+		var item persistedItem
+		err := dec.Decode(&item)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		c.SetWithExpiration(item.Key, item.Value, item.TTL)
+	}
+}
+
+// SaveFile is a convenience wrapper around Save that writes the snapshot to path.
+func (c *Cache) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.Save(f)
+}
+
+// LoadFile is a convenience wrapper around Load that reads the snapshot from path.
+func (c *Cache) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.Load(f)
+}
+
+// NewFrom builds a Cache seeded with items, e.g. to restore one from a snapshot
+// in one shot rather than constructing an empty cache and calling Load. Unlike
+// NewCache, it seeds items before the cleanup goroutine starts, so there's no
+// window where the janitor can run against a half-populated cache.
+func NewFrom(defaultExpiration, cleanupInterval time.Duration, clock clockwork.Clock, items map[string]Item, opts ...Option) *Cache {
+	cache := &Cache{
+		items:             make(map[string]Item, len(items)),
+		generations:       make(map[string]uint64),
+		defaultExpiration: defaultExpiration,
+		cleanupInterval:   cleanupInterval,
+		stopCleanup:       make(chan any),
+		clock:             clock,
+		logger:            setupLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(cache)
+	}
+
+	for k, v := range items {
+		cache.items[k] = v
+		cache.nextGeneration++
+		cache.generations[k] = cache.nextGeneration
+		if 0 < v.Expiration {
+			heap.Push(&cache.expirations, expirationEntry{key: k, expiration: v.Expiration, generation: cache.generations[k]})
+		}
+		if cache.capacityEnabled() {
+			cache.trackCapacitySet(k, true)
+		}
+	}
+
+	if cleanupInterval > 0 {
+		go cache.startCleanupTimer()
+	}
+
+	return cache
+}
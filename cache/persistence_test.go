@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+	"time"
+
+	clockwork "github.com/jonboulle/clockwork"
+)
+
+func init() {
+	gob.Register("")
+}
+
+func TestSaveAndLoadRoundTrip(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	source := NewCache(0, 0, clock)
+	defer source.Stop()
+
+	source.SetWithExpiration("expiring", "expiring value", 1*time.Minute)
+	source.SetWithoutExpiration("forever", "forever value")
+
+	var buf bytes.Buffer
+	t.Run("when the cache is saved", func(t *testing.T) {
+		if err := source.Save(&buf); err != nil {
+			t.Fatalf("Save returned an error: %v", err)
+		}
+
+		t.Run("when a new clock has advanced past the save time and the snapshot is loaded into a fresh cache", func(t *testing.T) {
+			destClock := clockwork.NewFakeClock()
+			destClock.Advance(1 * time.Hour)
+			dest := NewCache(0, 0, destClock)
+			defer dest.Stop()
+
+			if err := dest.Load(&buf); err != nil {
+				t.Fatalf("Load returned an error: %v", err)
+			}
+
+			t.Run("THEN the no-expiration item survived", func(t *testing.T) {
+				if val, found := dest.Get("forever"); !found || val != "forever value" {
+					t.Errorf("Expected `forever value`, got %v, found=%v", val, found)
+				}
+			})
+
+			t.Run("THEN the expiring item's remaining TTL was re-anchored to the new clock", func(t *testing.T) {
+				if val, found := dest.Get("expiring"); !found || val != "expiring value" {
+					t.Errorf("Expected `expiring value`, got %v, found=%v", val, found)
+				}
+			})
+		})
+	})
+}
+
+func TestSaveSkipsAlreadyExpiredItems(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	source := NewCache(0, 0, clock)
+	defer source.Stop()
+
+	source.SetWithExpiration("gone", "gone value", 1*time.Second)
+	clock.Advance(2 * time.Second)
+
+	var buf bytes.Buffer
+	t.Run("when an already-expired item is saved", func(t *testing.T) {
+		if err := source.Save(&buf); err != nil {
+			t.Fatalf("Save returned an error: %v", err)
+		}
+
+		t.Run("when loaded into a fresh cache", func(t *testing.T) {
+			dest := NewCache(0, 0, clockwork.NewFakeClock())
+			defer dest.Stop()
+			if err := dest.Load(&buf); err != nil {
+				t.Fatalf("Load returned an error: %v", err)
+			}
+
+			t.Run("THEN the expired item was never persisted", func(t *testing.T) {
+				if _, found := dest.Get("gone"); found {
+					t.Errorf("Expected `gone` to not have been saved")
+				}
+			})
+		})
+	})
+}
+
+func TestNewFrom(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	items := map[string]Item{
+		"foo": {Value: "foo value", Expiration: clock.Now().Add(1 * time.Minute).UnixNano()},
+		"bar": {Value: "bar value"},
+	}
+
+	cache := NewFrom(0, 0, clock, items)
+	defer cache.Stop()
+
+	t.Run("THEN both seeded items are immediately available", func(t *testing.T) {
+		if val, found := cache.Get("foo"); !found || val != "foo value" {
+			t.Errorf("Expected `foo value`, got %v, found=%v", val, found)
+		}
+		if val, found := cache.Get("bar"); !found || val != "bar value" {
+			t.Errorf("Expected `bar value`, got %v, found=%v", val, found)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"hash/fnv"
+	"runtime"
+	"time"
+
+	clockwork "github.com/jonboulle/clockwork"
+)
+
+// ShardedCache spreads keys across a fixed number of independent Cache instances
+// (shards), each with its own mutex, so that concurrent Set/Get traffic on
+// different keys doesn't serialize on one writer lock the way it does with a
+// single Cache. Each shard is just a Cache, so it runs its own cleanup goroutine;
+// there's no extra janitor coordinating across shards.
+type ShardedCache struct {
+	shards []*Cache
+}
+
+// DefaultShardCount is used by NewDefaultShardedCache, and is a reasonable
+// starting point for most workloads: enough shards to keep lock contention low
+// under concurrent writers without needlessly fragmenting small caches.
+var DefaultShardCount = runtime.GOMAXPROCS(0) * 4
+
+// Use NewDefaultShardedCache or this to create a ShardedCache instance, prefer not to reference it directly
+func NewShardedCache(numShards int, defaultExpiration, cleanupInterval time.Duration, clock clockwork.Clock) *ShardedCache {
+	if numShards < 1 {
+		numShards = 1
+	}
+
+	shards := make([]*Cache, numShards)
+	for i := range shards {
+		shards[i] = NewCache(defaultExpiration, cleanupInterval, clock)
+	}
+
+	return &ShardedCache{shards: shards}
+}
+
+// Create a sharded cache with some obvious defaults set. This is probably the version that you want.
+// See NewShardedCache for more complex version
+func NewDefaultShardedCache(defaultExpiration time.Duration) *ShardedCache {
+	return NewShardedCache(DefaultShardCount, defaultExpiration, 30*time.Second, clockwork.NewRealClock())
+}
+
+// shardFor routes a key to one of the cache's shards via fnv-1a, which is cheap
+// and spreads keys evenly enough for lock-contention purposes.
+func (c *ShardedCache) shardFor(key string) *Cache {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return c.shards[h.Sum32()%uint32(len(c.shards))]
+}
+
+// Add an item to the cache with the default expiration time.
+// If the item already exists in the cache it will be overridden and its expiration time updated.
+func (c *ShardedCache) Set(key string, value any) {
+	c.shardFor(key).Set(key, value)
+}
+
+// Add an item to the cache with a custom expiration time.
+// If expiration is 0, the item never expires.
+func (c *ShardedCache) SetWithExpiration(key string, value any, expiration time.Duration) {
+	c.shardFor(key).SetWithExpiration(key, value, expiration)
+}
+
+// Adds an item to the cache that never expires
+func (c *ShardedCache) SetWithoutExpiration(key string, value any) {
+	c.shardFor(key).SetWithoutExpiration(key, value)
+}
+
+// Manually remove an item from the cache. You will usually not have to do this and can either overwrite values in the cache and/or wait for them to expire
+func (c *ShardedCache) Delete(key string) {
+	c.shardFor(key).Delete(key)
+}
+
+// Return cached item and a boolean indicating whether the key was found
+func (c *ShardedCache) Get(key string) (any, bool) {
+	return c.shardFor(key).Get(key)
+}
+
+// Stops the cleanup goroutine of every shard and properly disposes of the cache
+func (c *ShardedCache) Stop() {
+	for _, shard := range c.shards {
+		shard.Stop()
+	}
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	clockwork "github.com/jonboulle/clockwork"
+)
+
+// These benchmarks compare the single-mutex Cache against ShardedCache under
+// concurrent writers (run with -cpu > 1, i.e. GOMAXPROCS > 1, to see the
+// contention difference; at GOMAXPROCS=1 there's nothing for sharding to buy).
+
+func BenchmarkCacheSetParallel(b *testing.B) {
+	cache := NewCache(5*time.Minute, 0, clockwork.NewRealClock())
+	defer cache.Stop()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.Set("key-"+strconv.Itoa(i%1000), i)
+			i++
+		}
+	})
+}
+
+func BenchmarkShardedCacheSetParallel(b *testing.B) {
+	cache := NewDefaultShardedCache(5 * time.Minute)
+	defer cache.Stop()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.Set("key-"+strconv.Itoa(i%1000), i)
+			i++
+		}
+	})
+}
+
+func BenchmarkCacheGetParallel(b *testing.B) {
+	cache := NewCache(5*time.Minute, 0, clockwork.NewRealClock())
+	defer cache.Stop()
+	for i := 0; i < 1000; i++ {
+		cache.Set("key-"+strconv.Itoa(i), i)
+	}
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.Get("key-" + strconv.Itoa(i%1000))
+			i++
+		}
+	})
+}
+
+func BenchmarkShardedCacheGetParallel(b *testing.B) {
+	cache := NewDefaultShardedCache(5 * time.Minute)
+	defer cache.Stop()
+	for i := 0; i < 1000; i++ {
+		cache.Set("key-"+strconv.Itoa(i), i)
+	}
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.Get("key-" + strconv.Itoa(i%1000))
+			i++
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	clockwork "github.com/jonboulle/clockwork"
+)
+
+func TestShardedCacheBasicOperations(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewShardedCache(4, 1*time.Second, 2*time.Second, clock)
+	defer cache.Stop()
+
+	t.Run("when user puts items under the keys `foo` and `bar` into the cache", func(t *testing.T) {
+		cache.Set("foo", "foo value")
+		cache.Set("bar", "bar value")
+
+		t.Run("THEN both keys land in the cache regardless of which shard they hash to", func(t *testing.T) {
+			if val, found := cache.Get("foo"); !found || val != "foo value" {
+				t.Errorf("Expected to find `foo value` under `foo`, got %v, found=%v", val, found)
+			}
+			if val, found := cache.Get("bar"); !found || val != "bar value" {
+				t.Errorf("Expected to find `bar value` under `bar`, got %v, found=%v", val, found)
+			}
+		})
+
+		t.Run("when user deletes `foo`", func(t *testing.T) {
+			cache.Delete("foo")
+
+			t.Run("THEN `foo` is no longer found", func(t *testing.T) {
+				if _, found := cache.Get("foo"); found {
+					t.Errorf("Expected `foo` to be deleted")
+				}
+			})
+		})
+	})
+
+	t.Run("when 1100ms elapses after setting `baz`", func(t *testing.T) {
+		cache.Set("baz", "baz value")
+		clock.Advance(1100 * time.Millisecond)
+
+		t.Run("THEN `baz` is expired", func(t *testing.T) {
+			if _, found := cache.Get("baz"); found {
+				t.Errorf("Expected `baz` to be expired")
+			}
+		})
+	})
+}
+
+func TestShardedCacheSingleShardIsValid(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewShardedCache(0, 1*time.Second, 2*time.Second, clock)
+	defer cache.Stop()
+
+	t.Run("when requesting 0 shards", func(t *testing.T) {
+		t.Run("THEN it falls back to a single shard instead of panicking", func(t *testing.T) {
+			if len(cache.shards) != 1 {
+				t.Errorf("Expected 1 shard, got %d", len(cache.shards))
+			}
+		})
+	})
+}
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	clockwork "github.com/jonboulle/clockwork"
+)
+
+// TypedCache is the generic counterpart to Cache: keys and values are statically
+// typed instead of being boxed as `any`, which avoids the type-assertion every
+// caller of Cache.Get otherwise has to do (and the interface-boxing overhead that
+// comes with it). Cache is left as its own, independently-maintained implementation
+// rather than being rewritten on top of TypedCache[string, any], since Go doesn't
+// allow a generic type to share an identifier with a non-generic one - so this
+// can't literally be named `Cache[K, V]` alongside the existing `Cache` - and
+// Cache keeps gaining its own cache-wide features (sharding, eviction, ...) that
+// would otherwise need to be threaded through the generic type too.
+type TypedCache[K comparable, V any] struct {
+	items             map[K]TypedItem[V]
+	defaultExpiration time.Duration
+	cleanupInterval   time.Duration
+	stopCleanup       chan any
+	clock             clockwork.Clock
+	mu                sync.RWMutex
+	logger            *slog.Logger
+}
+
+// TypedItem is the generic counterpart to Item.
+type TypedItem[V any] struct {
+	Value      V
+	Expiration int64
+}
+
+// Use NewDefaultTypedCache or this to create a TypedCache instance, prefer not to reference it directly
+func NewTypedCache[K comparable, V any](defaultExpiration, cleanupInterval time.Duration, clock clockwork.Clock) *TypedCache[K, V] {
+	cache := &TypedCache[K, V]{
+		items:             make(map[K]TypedItem[V]),
+		defaultExpiration: defaultExpiration,
+		cleanupInterval:   cleanupInterval,
+		stopCleanup:       make(chan any),
+		clock:             clock,
+		logger:            setupLogger(),
+	}
+
+	if cleanupInterval > 0 {
+		go cache.startCleanupTimer()
+	}
+
+	return cache
+}
+
+// Create a typed cache with some obvious defaults set. This is probably the version that you want.
+// See NewTypedCache for more complex version
+func NewDefaultTypedCache[K comparable, V any](defaultExpiration time.Duration) *TypedCache[K, V] {
+	return NewTypedCache[K, V](defaultExpiration, 30*time.Second, clockwork.NewRealClock())
+}
+
+// Add an item to the cache with the default expiration time.
+// If the item already exists in the cache it will be overridden and its expiration time updated.
+func (c *TypedCache[K, V]) Set(key K, value V) {
+	c.SetWithExpiration(key, value, c.defaultExpiration)
+}
+
+// Add an item to the cache with a custom expiration time.
+// If expiration is 0, the item never expires.
+func (c *TypedCache[K, V]) SetWithExpiration(key K, value V, expiration time.Duration) {
+	var exp int64
+	if 0 < expiration {
+		exp = c.clock.Now().Add(expiration).UnixNano()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = TypedItem[V]{
+		Value:      value,
+		Expiration: exp,
+	}
+}
+
+// Adds an item to the cache that never expires
+func (c *TypedCache[K, V]) SetWithoutExpiration(key K, value V) {
+	c.SetWithExpiration(key, value, 0)
+}
+
+// Manually remove an item from the cache. You will usually not have to do this and can either overwrite values in the cache and/or wait for them to expire
+func (c *TypedCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
+
+// Return cached item and a boolean indicating whether the key was found
+func (c *TypedCache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, found := c.items[key]
+	if !found {
+		var zero V
+		return zero, false
+	}
+
+	if 0 < item.Expiration && item.Expiration < c.clock.Now().UnixNano() {
+		c.logger.Debug("Item with key found but expired. Not returning.")
+		var zero V
+		return zero, false
+	}
+
+	return item.Value, true
+}
+
+// starts the cleanup timer
+func (c *TypedCache[K, V]) startCleanupTimer() {
+	ticker := c.clock.NewTicker(c.cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.Chan():
+			c.deleteExpired()
+		case <-c.stopCleanup:
+			return
+		}
+	}
+}
+
+// Force eviction of all expired items
+func (c *TypedCache[K, V]) deleteExpired() {
+	c.logger.Debug("Cleaning up expired items.")
+	now := c.clock.Now().UnixNano()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, v := range c.items {
+		if 0 < v.Expiration && v.Expiration < now {
+			delete(c.items, k)
+		}
+	}
+}
+
+// Stops the cleanup and properly disposes of the cache
+func (c *TypedCache[K, V]) Stop() {
+	if 0 < c.cleanupInterval {
+		c.stopCleanup <- true
+	}
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	clockwork "github.com/jonboulle/clockwork"
+)
+
+func TestTypedCacheBasicOperations(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewTypedCache[string, int](1*time.Second, 2*time.Second, clock)
+	defer cache.Stop()
+
+	t.Run("when user puts an item under the key `foo` into the cache", func(t *testing.T) {
+		cache.Set("foo", 42)
+
+		t.Run("THEN the typed value is returned without a type assertion", func(t *testing.T) {
+			val, found := cache.Get("foo")
+			if !found {
+				t.Errorf("Expected to find cached value under key `foo` but one was not found")
+			}
+			if val != 42 {
+				t.Errorf("Expected 42, got %d", val)
+			}
+		})
+
+		t.Run("when 1100ms elapses", func(t *testing.T) {
+			clock.Advance(1100 * time.Millisecond)
+
+			t.Run("THEN `foo` is no longer found", func(t *testing.T) {
+				if _, found := cache.Get("foo"); found {
+					t.Errorf("Expected `foo` to be expired")
+				}
+			})
+		})
+	})
+}
+
+func TestTypedCacheZeroValueOnMiss(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewTypedCache[string, string](1*time.Second, 2*time.Second, clock)
+	defer cache.Stop()
+
+	t.Run("when the key was never set", func(t *testing.T) {
+		val, found := cache.Get("missing")
+
+		t.Run("THEN found is false and value is the zero value", func(t *testing.T) {
+			if found {
+				t.Errorf("Expected not to find `missing`")
+			}
+			if val != "" {
+				t.Errorf("Expected zero value, got %q", val)
+			}
+		})
+	})
+}